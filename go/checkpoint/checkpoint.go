@@ -0,0 +1,288 @@
+// Package checkpoint saves and restores bettirdl.Kernel state to HDF5
+// files. It lives outside the bettirdl package specifically so that
+// NewKernel, Run, RunContext, Replay and the scenario DSL stay
+// buildable and testable without a libhdf5 install -- only code that
+// imports this package pulls in gonum.org/v1/hdf5 (and, transitively,
+// cgo against libhdf5).
+package checkpoint
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bettirdl "github.com/betti-labs/betti-rdl"
+	"gonum.org/v1/hdf5"
+)
+
+const (
+	processesDataset = "/state/processes"
+	eventsDataset    = "/state/events"
+	telemetryGroup   = "/telemetry"
+)
+
+// state is the decoded form of the flat buffer produced by
+// bettirdl.Kernel.SerializeState: the per-process state over the
+// 32x32x32 torus (indexed by pid) and the pending event queue as (x, y,
+// z, value) tuples.
+type state struct {
+	currentTime uint64
+	processes   []int32
+	events      []int32 // flattened (x, y, z, value) quadruples
+}
+
+// Save serializes kernel's full simulation state -- current time,
+// per-process state over the 32x32x32 torus, the pending event queue,
+// and telemetry counters -- to an HDF5 file at path, following the
+// layout:
+//
+//	/state/processes  per-process state, indexed by pid
+//	/state/events     pending events as flattened (x, y, z, value) quads
+//	/telemetry        attributes: events_processed, current_time,
+//	                  process_count, memory_used
+//
+// This mirrors ARTIQ's experiment archives and lets Python/Julia/C++
+// consumers load a run's final state to verify determinism claims across
+// language bindings, which Kernel.GetTelemetry's four scalars are not
+// enough to do on their own.
+func Save(kernel *bettirdl.Kernel, path string) error {
+	raw, err := kernel.SerializeState()
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: %w", err)
+	}
+	s, err := decodeState(raw)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: decode kernel state: %w", err)
+	}
+
+	f, err := hdf5.CreateFile(path, hdf5.F_ACC_TRUNC)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create checkpoint file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := writeInt32Dataset(f, processesDataset, s.processes); err != nil {
+		return err
+	}
+	if err := writeInt32Dataset(f, eventsDataset, s.events); err != nil {
+		return err
+	}
+	if err := writeTelemetryAttrs(f, telemetryGroup, kernel.GetTelemetry(), s.currentTime); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Load reads an HDF5 checkpoint written by Save and returns a new Kernel
+// restored to that state.
+func Load(path string) (*bettirdl.Kernel, error) {
+	f, err := hdf5.OpenFile(path, hdf5.F_ACC_RDONLY)
+	if err != nil {
+		return nil, fmt.Errorf("bettirdl/checkpoint: open checkpoint file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	processes, err := readInt32Dataset(f, processesDataset)
+	if err != nil {
+		return nil, err
+	}
+	events, err := readInt32Dataset(f, eventsDataset)
+	if err != nil {
+		return nil, err
+	}
+	currentTime, err := readTelemetryCurrentTime(f, telemetryGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := encodeState(state{
+		currentTime: currentTime,
+		processes:   processes,
+		events:      events,
+	})
+
+	k, err := bettirdl.LoadState(raw)
+	if err != nil {
+		return nil, fmt.Errorf("bettirdl/checkpoint: load checkpoint %s: %w", path, err)
+	}
+	return k, nil
+}
+
+// decodeState parses the flat buffer produced by
+// bettirdl.Kernel.SerializeState:
+//
+//	uint64   current time
+//	uint32   process count, followed by that many int32 process states
+//	uint32   event count, followed by that many (x, y, z, value) int32 quads
+func decodeState(raw []byte) (state, error) {
+	if len(raw) < 8 {
+		return state{}, fmt.Errorf("state buffer too short: %d bytes", len(raw))
+	}
+	r := bytesReader(raw)
+
+	var s state
+	s.currentTime = binary.LittleEndian.Uint64(r.next(8))
+
+	processCount := binary.LittleEndian.Uint32(r.next(4))
+	s.processes = make([]int32, processCount)
+	for i := range s.processes {
+		s.processes[i] = int32(binary.LittleEndian.Uint32(r.next(4)))
+	}
+
+	eventCount := binary.LittleEndian.Uint32(r.next(4))
+	s.events = make([]int32, eventCount*4)
+	for i := range s.events {
+		s.events[i] = int32(binary.LittleEndian.Uint32(r.next(4)))
+	}
+
+	if err := r.err(); err != nil {
+		return state{}, err
+	}
+	return s, nil
+}
+
+// encodeState is the inverse of decodeState.
+func encodeState(s state) []byte {
+	buf := make([]byte, 8+4+len(s.processes)*4+4+len(s.events)*4)
+	binary.LittleEndian.PutUint64(buf[0:8], s.currentTime)
+	off := 8
+	binary.LittleEndian.PutUint32(buf[off:off+4], uint32(len(s.processes)))
+	off += 4
+	for _, v := range s.processes {
+		binary.LittleEndian.PutUint32(buf[off:off+4], uint32(v))
+		off += 4
+	}
+	binary.LittleEndian.PutUint32(buf[off:off+4], uint32(len(s.events)/4))
+	off += 4
+	for _, v := range s.events {
+		binary.LittleEndian.PutUint32(buf[off:off+4], uint32(v))
+		off += 4
+	}
+	return buf
+}
+
+// stateBufferReader is a tiny bounds-checked cursor over a byte slice,
+// used by decodeState to avoid repeating length checks inline.
+type stateBufferReader struct {
+	buf        []byte
+	pos        int
+	outOfRange bool
+}
+
+func bytesReader(buf []byte) *stateBufferReader {
+	return &stateBufferReader{buf: buf}
+}
+
+func (r *stateBufferReader) next(n int) []byte {
+	if r.outOfRange || r.pos+n > len(r.buf) {
+		r.outOfRange = true
+		return make([]byte, n)
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *stateBufferReader) err() error {
+	if r.outOfRange {
+		return fmt.Errorf("state buffer truncated")
+	}
+	return nil
+}
+
+func writeInt32Dataset(f *hdf5.File, name string, data []int32) error {
+	dataspace, err := hdf5.CreateSimpleDataspace([]uint{uint(len(data))}, nil)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create dataspace for %s: %w", name, err)
+	}
+	defer dataspace.Close()
+
+	dataset, err := f.CreateDataset(name, hdf5.T_NATIVE_INT32, dataspace)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create dataset %s: %w", name, err)
+	}
+	defer dataset.Close()
+
+	if err := dataset.Write(&data); err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: write dataset %s: %w", name, err)
+	}
+	return nil
+}
+
+func readInt32Dataset(f *hdf5.File, name string) ([]int32, error) {
+	dataset, err := f.OpenDataset(name)
+	if err != nil {
+		return nil, fmt.Errorf("bettirdl/checkpoint: open dataset %s: %w", name, err)
+	}
+	defer dataset.Close()
+
+	dataspace := dataset.Space()
+	defer dataspace.Close()
+
+	count := dataspace.SimpleExtentNPoints()
+	data := make([]int32, count)
+	if err := dataset.Read(&data); err != nil {
+		return nil, fmt.Errorf("bettirdl/checkpoint: read dataset %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func writeTelemetryAttrs(f *hdf5.File, group string, tel bettirdl.Telemetry, currentTime uint64) error {
+	g, err := f.CreateGroup(group)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create group %s: %w", group, err)
+	}
+	defer g.Close()
+
+	attrs := map[string]uint64{
+		"events_processed": tel.EventsProcessed,
+		"current_time":     currentTime,
+		"process_count":    tel.ProcessCount,
+		"memory_used":      tel.MemoryUsed,
+	}
+	for name, value := range attrs {
+		if err := writeUint64Attr(g, name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeUint64Attr(g *hdf5.Group, name string, value uint64) error {
+	dataspace, err := hdf5.CreateSimpleDataspace([]uint{1}, nil)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create dataspace for attr %s: %w", name, err)
+	}
+	defer dataspace.Close()
+
+	attr, err := g.CreateAttribute(name, hdf5.T_NATIVE_UINT64, dataspace)
+	if err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: create attr %s: %w", name, err)
+	}
+	defer attr.Close()
+
+	values := []uint64{value}
+	if err := attr.Write(&values, hdf5.T_NATIVE_UINT64); err != nil {
+		return fmt.Errorf("bettirdl/checkpoint: write attr %s: %w", name, err)
+	}
+	return nil
+}
+
+func readTelemetryCurrentTime(f *hdf5.File, group string) (uint64, error) {
+	g, err := f.OpenGroup(group)
+	if err != nil {
+		return 0, fmt.Errorf("bettirdl/checkpoint: open group %s: %w", group, err)
+	}
+	defer g.Close()
+
+	attr, err := g.OpenAttribute("current_time")
+	if err != nil {
+		return 0, fmt.Errorf("bettirdl/checkpoint: open attr current_time: %w", err)
+	}
+	defer attr.Close()
+
+	values := make([]uint64, 1)
+	if err := attr.Read(&values, hdf5.T_NATIVE_UINT64); err != nil {
+		return 0, fmt.Errorf("bettirdl/checkpoint: read attr current_time: %w", err)
+	}
+	return values[0], nil
+}
@@ -5,8 +5,30 @@ import (
 	"strings"
 
 	"github.com/betti-labs/betti-rdl"
+	"github.com/betti-labs/betti-rdl/scenario"
 )
 
+// basicScenario drives the same 10-process distributed counter this
+// example always has, but as a scenario program instead of a hand
+// rolled spawn loop, so it stays in lockstep with the DSL the rest of
+// the tooling (bettirdl-run, regression scenarios) uses.
+const basicScenario = `
+spawn 0 0 0
+spawn 1 0 0
+spawn 2 0 0
+spawn 3 0 0
+spawn 4 0 0
+spawn 5 0 0
+spawn 6 0 0
+spawn 7 0 0
+spawn 8 0 0
+spawn 9 0 0
+inject 0 0 0 value=1
+inject 0 0 0 value=2
+inject 0 0 0 value=3
+run 100
+`
+
 func main() {
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Println("   BETTI-RDL GO EXAMPLE")
@@ -17,21 +39,18 @@ func main() {
 	kernel := bettirdl.NewKernel()
 	defer kernel.Close()
 
-	// Spawn processes
-	fmt.Println("[SETUP] Spawning 10 processes...")
-	for i := 0; i < 10; i++ {
-		kernel.SpawnProcess(i, 0, 0)
+	program, err := scenario.Parse(scenario.Tokenize(strings.NewReader(basicScenario)))
+	if err != nil {
+		panic(err)
 	}
 
-	// Inject events
+	// Spawn processes, inject events, run computation
+	fmt.Println("[SETUP] Spawning 10 processes...")
 	fmt.Println("[INJECT] Sending events with values 1, 2, 3...")
-	kernel.InjectEvent(0, 0, 0, 1)
-	kernel.InjectEvent(0, 0, 0, 2)
-	kernel.InjectEvent(0, 0, 0, 3)
-
-	// Run computation
 	fmt.Println("\n[COMPUTE] Running distributed counter...")
-	kernel.Run(100)
+	if err := program.Run(kernel, nil); err != nil {
+		panic(err)
+	}
 
 	// Display results
 	fmt.Println("\n[RESULTS]")
@@ -0,0 +1,40 @@
+// Command bettirdl-replay replays a trace captured by
+// bettirdl.NewRecordingKernel against a fresh kernel and reports the
+// first point of divergence, if any.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/betti-labs/betti-rdl"
+)
+
+func main() {
+	trace := flag.String("trace", "", "path to a trace file produced by bettirdl.NewRecordingKernel")
+	flag.Parse()
+	if *trace == "" {
+		fmt.Fprintln(os.Stderr, "usage: bettirdl-replay -trace <file>")
+		os.Exit(2)
+	}
+
+	kernel := bettirdl.NewKernel()
+	defer kernel.Close()
+
+	err := bettirdl.Replay(*trace, kernel)
+	if err == nil {
+		fmt.Println("replay matched recorded trace exactly")
+		return
+	}
+
+	var diverge *bettirdl.DivergenceError
+	if errors.As(err, &diverge) {
+		fmt.Println(diverge.Error())
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "bettirdl-replay: %v\n", err)
+	os.Exit(1)
+}
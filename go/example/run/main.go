@@ -0,0 +1,72 @@
+// Command bettirdl-run executes a scenario file (see the bettirdl/scenario
+// package) against a fresh kernel and writes the resulting telemetry as
+// CSV, replacing the ad-hoc spawn/inject loops in earlier example
+// programs with a stable, regression-friendly input format.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/betti-labs/betti-rdl"
+	"github.com/betti-labs/betti-rdl/checkpoint"
+	"github.com/betti-labs/betti-rdl/scenario"
+)
+
+func main() {
+	scenarioPath := flag.String("scenario", "", "path to a scenario file")
+	csvPath := flag.String("csv", "", "path to write telemetry CSV to (default: stdout)")
+	flag.Parse()
+	if *scenarioPath == "" {
+		fmt.Fprintln(os.Stderr, "usage: bettirdl-run -scenario <file> [-csv <file>]")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*scenarioPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bettirdl-run: %v\n", err)
+		os.Exit(1)
+	}
+	tokens := scenario.Tokenize(f)
+	f.Close()
+
+	program, err := scenario.Parse(tokens)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bettirdl-run: %v\n", err)
+		os.Exit(1)
+	}
+
+	kernel := bettirdl.NewKernel()
+	defer kernel.Close()
+
+	if err := program.Run(kernel, checkpoint.Save); err != nil {
+		fmt.Fprintf(os.Stderr, "bettirdl-run: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *csvPath != "" {
+		csvFile, err := os.Create(*csvPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bettirdl-run: %v\n", err)
+			os.Exit(1)
+		}
+		defer csvFile.Close()
+		out = csvFile
+	}
+
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	tel := kernel.GetTelemetry()
+	w.Write([]string{"events_processed", "current_time", "process_count", "memory_used"})
+	w.Write([]string{
+		strconv.FormatUint(tel.EventsProcessed, 10),
+		strconv.FormatUint(tel.CurrentTime, 10),
+		strconv.FormatUint(tel.ProcessCount, 10),
+		strconv.FormatUint(tel.MemoryUsed, 10),
+	})
+}
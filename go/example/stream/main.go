@@ -0,0 +1,51 @@
+// Command bettirdl-stream runs a kernel via RunContext and prints each
+// telemetry/host-resource Sample as it arrives, in place of the
+// one-shot "TELEMETRY,..." print used by the other example programs.
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/betti-labs/betti-rdl"
+)
+
+func main() {
+	kernel := bettirdl.NewKernel()
+	defer kernel.Close()
+
+	for i := 0; i < 10; i++ {
+		kernel.SpawnProcess(i, 0, 0)
+	}
+	kernel.InjectEvent(0, 0, 0, 1)
+	kernel.InjectEvent(0, 0, 0, 2)
+	kernel.InjectEvent(0, 0, 0, 3)
+
+	samples := make(chan bettirdl.Sample, 16)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for s := range samples {
+			fmt.Printf("SAMPLE,%d,%d,%d,%.1f,%d\n",
+				s.Telemetry.EventsProcessed, s.Telemetry.CurrentTime, s.Telemetry.ProcessCount,
+				s.CPUPercent, s.RSSBytes)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	processed, err := kernel.RunContext(ctx, 100, bettirdl.RunOptions{
+		SampleInterval: 50 * time.Millisecond,
+		Sink:           samples,
+	})
+	close(samples)
+	<-done
+
+	if err != nil {
+		fmt.Printf("RunContext stopped early after %d events: %v\n", processed, err)
+		return
+	}
+	fmt.Printf("RunContext processed %d events\n", processed)
+}
@@ -0,0 +1,8 @@
+//go:build linux || freebsd || netbsd
+
+package bettirdl
+
+/*
+#cgo LDFLAGS: -L../build/shared/lib -L../src/cpp_kernel/build -lbetti_rdl_c -lstdc++
+*/
+import "C"
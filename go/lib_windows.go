@@ -0,0 +1,8 @@
+//go:build windows
+
+package bettirdl
+
+/*
+#cgo LDFLAGS: -L../build/shared/lib -L../build/shared/bin -L../src/cpp_kernel/build -lbetti_rdl_c -lstdc++
+*/
+import "C"
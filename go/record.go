@@ -0,0 +1,214 @@
+package bettirdl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// opTag identifies which mutating Kernel call a record represents.
+type opTag uint8
+
+const (
+	opSpawnProcess opTag = iota + 1
+	opInjectEvent
+	opRun
+)
+
+// RecordingKernel wraps a Kernel and appends every mutating API call
+// (SpawnProcess, InjectEvent, Run) to a trace file as a length-prefixed
+// record: the op tag, its arguments, and -- for Run -- the telemetry and
+// per-pid process state observed immediately afterward. A trace produced
+// by a RecordingKernel can be fed to Replay to bisect nondeterminism in
+// the C++ kernel without re-running the whole program.
+type RecordingKernel struct {
+	*Kernel
+
+	f   *os.File
+	err error
+}
+
+// NewRecordingKernel creates a kernel whose mutating calls are traced to path.
+func NewRecordingKernel(path string) (*RecordingKernel, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("bettirdl: create trace file: %w", err)
+	}
+	return &RecordingKernel{
+		Kernel: NewKernel(),
+		f:      f,
+	}, nil
+}
+
+// Err returns the first error encountered while writing the trace, if any.
+func (rk *RecordingKernel) Err() error {
+	return rk.err
+}
+
+// SpawnProcess spawns a process and records the call.
+func (rk *RecordingKernel) SpawnProcess(x, y, z int) {
+	rk.Kernel.SpawnProcess(x, y, z)
+	rk.appendRecord(opSpawnProcess, [4]int32{int32(x), int32(y), int32(z), 0})
+}
+
+// InjectEvent injects an event and records the call.
+func (rk *RecordingKernel) InjectEvent(x, y, z, value int) {
+	rk.Kernel.InjectEvent(x, y, z, value)
+	rk.appendRecord(opInjectEvent, [4]int32{int32(x), int32(y), int32(z), int32(value)})
+}
+
+// Run executes computation and records the call along with the resulting
+// telemetry and per-pid process state so Replay can detect divergence.
+func (rk *RecordingKernel) Run(maxEvents int) int {
+	n := rk.Kernel.Run(maxEvents)
+	rk.appendRecord(opRun, [4]int32{int32(maxEvents), 0, 0, 0})
+	return n
+}
+
+// Close flushes the trace file and closes the underlying kernel.
+func (rk *RecordingKernel) Close() {
+	if rk.f != nil {
+		if err := rk.f.Close(); err != nil && rk.err == nil {
+			rk.err = err
+		}
+		rk.f = nil
+	}
+	rk.Kernel.Close()
+}
+
+func (rk *RecordingKernel) appendRecord(op opTag, args [4]int32) {
+	if rk.err != nil {
+		return
+	}
+
+	var tel Telemetry
+	var states []int32
+	if op == opRun {
+		tel = rk.Kernel.GetTelemetry()
+		states = make([]int32, tel.ProcessCount)
+		for pid := range states {
+			states[pid] = int32(rk.Kernel.ProcessState(pid))
+		}
+	}
+
+	payload := new(bytes.Buffer)
+	binary.Write(payload, binary.LittleEndian, op)
+	binary.Write(payload, binary.LittleEndian, args)
+	binary.Write(payload, binary.LittleEndian, tel.EventsProcessed)
+	binary.Write(payload, binary.LittleEndian, tel.CurrentTime)
+	binary.Write(payload, binary.LittleEndian, tel.ProcessCount)
+	binary.Write(payload, binary.LittleEndian, tel.MemoryUsed)
+	binary.Write(payload, binary.LittleEndian, uint32(len(states)))
+	for _, s := range states {
+		binary.Write(payload, binary.LittleEndian, s)
+	}
+
+	if err := binary.Write(rk.f, binary.LittleEndian, uint32(payload.Len())); err != nil {
+		rk.err = fmt.Errorf("bettirdl: write trace record length: %w", err)
+		return
+	}
+	if _, err := rk.f.Write(payload.Bytes()); err != nil {
+		rk.err = fmt.Errorf("bettirdl: write trace record: %w", err)
+	}
+}
+
+// DivergenceError reports the first point at which a replayed trace
+// disagreed with the kernel it was replayed against.
+type DivergenceError struct {
+	// Index is the zero-based index of the Run call at which the
+	// divergence was observed.
+	Index int
+	Field string
+	Got   uint64
+	Want  uint64
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("bettirdl: divergence at run #%d: %s got %d, want %d", e.Index, e.Field, e.Got, e.Want)
+}
+
+// Replay reads a trace recorded by a RecordingKernel, re-issues the same
+// SpawnProcess/InjectEvent/Run calls against target, and after each Run
+// compares the live telemetry and per-pid process state against the
+// recorded snapshot. It returns a *DivergenceError describing the first
+// mismatch, or nil if target reproduced the trace exactly.
+func Replay(path string, target *Kernel) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("bettirdl: open trace file: %w", err)
+	}
+	defer f.Close()
+
+	runIndex := 0
+	for {
+		var length uint32
+		if err := binary.Read(f, binary.LittleEndian, &length); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("bettirdl: read trace record length: %w", err)
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(f, payload); err != nil {
+			return fmt.Errorf("bettirdl: read trace record: %w", err)
+		}
+		r := bytes.NewReader(payload)
+
+		var op opTag
+		var args [4]int32
+		var tel Telemetry
+		var stateCount uint32
+		fields := []struct {
+			name string
+			data any
+		}{
+			{"op", &op},
+			{"args", &args},
+			{"EventsProcessed", &tel.EventsProcessed},
+			{"CurrentTime", &tel.CurrentTime},
+			{"ProcessCount", &tel.ProcessCount},
+			{"MemoryUsed", &tel.MemoryUsed},
+			{"stateCount", &stateCount},
+		}
+		for _, field := range fields {
+			if err := binary.Read(r, binary.LittleEndian, field.data); err != nil {
+				return fmt.Errorf("bettirdl: decode trace record %d field %s: %w", runIndex, field.name, err)
+			}
+		}
+		states := make([]int32, stateCount)
+		if err := binary.Read(r, binary.LittleEndian, &states); err != nil {
+			return fmt.Errorf("bettirdl: decode trace record %d field states: %w", runIndex, err)
+		}
+
+		switch op {
+		case opSpawnProcess:
+			target.SpawnProcess(int(args[0]), int(args[1]), int(args[2]))
+		case opInjectEvent:
+			target.InjectEvent(int(args[0]), int(args[1]), int(args[2]), int(args[3]))
+		case opRun:
+			target.Run(int(args[0]))
+			live := target.GetTelemetry()
+			if live.EventsProcessed != tel.EventsProcessed {
+				return &DivergenceError{runIndex, "EventsProcessed", live.EventsProcessed, tel.EventsProcessed}
+			}
+			if live.CurrentTime != tel.CurrentTime {
+				return &DivergenceError{runIndex, "CurrentTime", live.CurrentTime, tel.CurrentTime}
+			}
+			if live.ProcessCount != tel.ProcessCount {
+				return &DivergenceError{runIndex, "ProcessCount", live.ProcessCount, tel.ProcessCount}
+			}
+			for pid, want := range states {
+				if got := int32(target.ProcessState(pid)); got != want {
+					return &DivergenceError{runIndex, fmt.Sprintf("ProcessState(%d)", pid), uint64(got), uint64(want)}
+				}
+			}
+			runIndex++
+		default:
+			return fmt.Errorf("bettirdl: unknown trace op %d at record %d", op, runIndex)
+		}
+	}
+}
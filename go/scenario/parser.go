@@ -0,0 +1,174 @@
+package scenario
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Command is a single parsed scenario statement.
+type Command interface {
+	command()
+}
+
+// SpawnCommand corresponds to a "spawn x y z" line.
+type SpawnCommand struct {
+	X, Y, Z int
+}
+
+// InjectCommand corresponds to an "inject x y z value=N" line.
+type InjectCommand struct {
+	X, Y, Z, Value int
+}
+
+// RunCommand corresponds to a "run maxEvents" line.
+type RunCommand struct {
+	MaxEvents int
+}
+
+// AssertCommand corresponds to an "assert field<op>value" line, e.g.
+// "assert events>=42". Field is one of events, time, processes, memory
+// (the GetTelemetry counters); Op is one of >=, <=, ==, !=, >, <.
+type AssertCommand struct {
+	Field string
+	Op    string
+	Value uint64
+}
+
+// CheckpointCommand corresponds to a "checkpoint path" line.
+type CheckpointCommand struct {
+	Path string
+}
+
+func (SpawnCommand) command()      {}
+func (InjectCommand) command()     {}
+func (RunCommand) command()        {}
+func (AssertCommand) command()     {}
+func (CheckpointCommand) command() {}
+
+// Program is the parsed form of a scenario file: an ordered list of
+// commands to drive a Kernel through, via Program.Run.
+type Program struct {
+	Commands []Command
+}
+
+var assertOperators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// Parse turns a token stream produced by Tokenize into a Program.
+// Tokens are grouped by source line, so each line of a scenario file
+// must contain exactly one command.
+func Parse(tokens []Token) (*Program, error) {
+	var prog Program
+	i := 0
+	for i < len(tokens) && tokens[i].Kind != EOF {
+		line := tokens[i].Line
+		start := i
+		for i < len(tokens) && tokens[i].Kind != EOF && tokens[i].Line == line {
+			i++
+		}
+		cmd, err := parseLine(tokens[start:i])
+		if err != nil {
+			return nil, fmt.Errorf("scenario: line %d: %w", line, err)
+		}
+		prog.Commands = append(prog.Commands, cmd)
+	}
+	return &prog, nil
+}
+
+func parseLine(words []Token) (Command, error) {
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	args := words[1:]
+	switch words[0].Text {
+	case "spawn":
+		coords, err := parseInts(args, 3)
+		if err != nil {
+			return nil, fmt.Errorf("spawn: %w", err)
+		}
+		return SpawnCommand{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+
+	case "inject":
+		if len(args) != 4 {
+			return nil, fmt.Errorf("inject: want \"inject x y z value=N\", got %d fields", len(args))
+		}
+		coords, err := parseInts(args[:3], 3)
+		if err != nil {
+			return nil, fmt.Errorf("inject: %w", err)
+		}
+		value, err := parseKeyValueInt(args[3].Text, "value")
+		if err != nil {
+			return nil, fmt.Errorf("inject: %w", err)
+		}
+		return InjectCommand{X: coords[0], Y: coords[1], Z: coords[2], Value: value}, nil
+
+	case "run":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("run: want \"run maxEvents\", got %d fields", len(args))
+		}
+		n, err := strconv.Atoi(args[0].Text)
+		if err != nil {
+			return nil, fmt.Errorf("run: invalid maxEvents %q: %w", args[0].Text, err)
+		}
+		return RunCommand{MaxEvents: n}, nil
+
+	case "assert":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("assert: want \"assert field<op>value\", got %d fields", len(args))
+		}
+		field, op, value, err := parseAssertion(args[0].Text)
+		if err != nil {
+			return nil, fmt.Errorf("assert: %w", err)
+		}
+		return AssertCommand{Field: field, Op: op, Value: value}, nil
+
+	case "checkpoint":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("checkpoint: want \"checkpoint path\", got %d fields", len(args))
+		}
+		return CheckpointCommand{Path: args[0].Text}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown command %q", words[0].Text)
+	}
+}
+
+func parseInts(words []Token, n int) ([]int, error) {
+	if len(words) != n {
+		return nil, fmt.Errorf("want %d integers, got %d", n, len(words))
+	}
+	out := make([]int, n)
+	for i, w := range words {
+		v, err := strconv.Atoi(w.Text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q: %w", w.Text, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func parseKeyValueInt(s, key string) (int, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[0] != key {
+		return 0, fmt.Errorf("expected %s=<int>, got %q", key, s)
+	}
+	return strconv.Atoi(parts[1])
+}
+
+func parseAssertion(s string) (field, op string, value uint64, err error) {
+	for _, candidate := range assertOperators {
+		idx := strings.Index(s, candidate)
+		if idx < 0 {
+			continue
+		}
+		rest := s[idx+len(candidate):]
+		v, err := strconv.ParseUint(rest, 10, 64)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("invalid assertion value %q: %w", rest, err)
+		}
+		return s[:idx], candidate, v, nil
+	}
+	return "", "", 0, fmt.Errorf("invalid assertion %q: expected an operator from %v", s, assertOperators)
+}
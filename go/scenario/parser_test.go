@@ -0,0 +1,110 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want Command
+	}{
+		{"spawn", "spawn 3 4 5", SpawnCommand{X: 3, Y: 4, Z: 5}},
+		{"inject", "inject 3 4 5 value=7", InjectCommand{X: 3, Y: 4, Z: 5, Value: 7}},
+		{"run", "run 100", RunCommand{MaxEvents: 100}},
+		{"assert", "assert events>=42", AssertCommand{Field: "events", Op: ">=", Value: 42}},
+		{"checkpoint", "checkpoint out.h5", CheckpointCommand{Path: "out.h5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prog, err := Parse(Tokenize(strings.NewReader(tt.line)))
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.line, err)
+			}
+			if len(prog.Commands) != 1 {
+				t.Fatalf("Parse(%q) produced %d commands, want 1", tt.line, len(prog.Commands))
+			}
+			if got := prog.Commands[0]; got != tt.want {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMultiLineProgram(t *testing.T) {
+	input := "spawn 0 0 0\ninject 0 0 0 value=1\nrun 10\nassert events>=1\n"
+	prog, err := Parse(Tokenize(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(prog.Commands) != 4 {
+		t.Fatalf("Parse produced %d commands, want 4: %#v", len(prog.Commands), prog.Commands)
+	}
+}
+
+func TestParseRejectsMalformedCommands(t *testing.T) {
+	tests := []string{
+		"spawn 1 2",            // too few coordinates
+		"inject 1 2 3",         // missing value=N
+		"inject 1 2 3 value=x", // non-integer value
+		"run",                  // missing maxEvents
+		"run abc",              // non-integer maxEvents
+		"assert events",        // missing operator/value
+		"checkpoint",           // missing path
+		"orbit 1 2 3",          // unknown command
+	}
+
+	for _, line := range tests {
+		t.Run(line, func(t *testing.T) {
+			if _, err := Parse(Tokenize(strings.NewReader(line))); err == nil {
+				t.Errorf("Parse(%q) succeeded, want an error", line)
+			}
+		})
+	}
+}
+
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantField string
+		wantOp    string
+		wantValue uint64
+		wantErr   bool
+	}{
+		// parseAssertion tries operators in a fixed order ([">=", "<=",
+		// "==", "!=", ">", "<"]) so that, e.g., "events>=42" is parsed
+		// as the ">=" operator rather than ">" followed by "=42".
+		{"events>=42", "events", ">=", 42, false},
+		{"events<=42", "events", "<=", 42, false},
+		{"events==42", "events", "==", 42, false},
+		{"events!=42", "events", "!=", 42, false},
+		{"events>42", "events", ">", 42, false},
+		{"events<42", "events", "<", 42, false},
+		{"events", "", "", 0, true},      // no operator
+		{"events>=", "", "", 0, true},    // no value
+		{"events>=abc", "", "", 0, true}, // non-numeric value
+		{">=42", "", ">=", 42, false},    // empty field is syntactically accepted
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			field, op, value, err := parseAssertion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseAssertion(%q) = (%q, %q, %d, nil), want an error", tt.input, field, op, value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAssertion(%q) returned error: %v", tt.input, err)
+			}
+			if field != tt.wantField || op != tt.wantOp || value != tt.wantValue {
+				t.Errorf("parseAssertion(%q) = (%q, %q, %d), want (%q, %q, %d)",
+					tt.input, field, op, value, tt.wantField, tt.wantOp, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package scenario
+
+import (
+	"fmt"
+
+	"github.com/betti-labs/betti-rdl"
+)
+
+// CheckpointFunc persists a kernel's state to path, backing the
+// "checkpoint" scenario command. scenario deliberately has no
+// dependency on how a checkpoint is written (HDF5, or anything else) --
+// see bettirdl/checkpoint.Save for a ready-made CheckpointFunc.
+type CheckpointFunc func(kernel *bettirdl.Kernel, path string) error
+
+// Run drives kernel through each command in the program in order,
+// stopping at the first error. A failing AssertCommand is reported as
+// an error, so a scenario also works as a regression check and not
+// just a workload generator. checkpoint is invoked for each
+// CheckpointCommand; pass nil if the program has none -- a nil
+// checkpoint paired with a CheckpointCommand is reported as a run
+// error, not a panic.
+func (p *Program) Run(kernel *bettirdl.Kernel, checkpoint CheckpointFunc) error {
+	for i, cmd := range p.Commands {
+		if err := runCommand(kernel, cmd, checkpoint); err != nil {
+			return fmt.Errorf("scenario: command %d (%T): %w", i, cmd, err)
+		}
+	}
+	return nil
+}
+
+func runCommand(kernel *bettirdl.Kernel, cmd Command, checkpoint CheckpointFunc) error {
+	switch c := cmd.(type) {
+	case SpawnCommand:
+		kernel.SpawnProcess(c.X, c.Y, c.Z)
+	case InjectCommand:
+		kernel.InjectEvent(c.X, c.Y, c.Z, c.Value)
+	case RunCommand:
+		kernel.Run(c.MaxEvents)
+	case AssertCommand:
+		return checkAssertion(kernel, c)
+	case CheckpointCommand:
+		if checkpoint == nil {
+			return fmt.Errorf("checkpoint %s: no CheckpointFunc configured", c.Path)
+		}
+		return checkpoint(kernel, c.Path)
+	default:
+		return fmt.Errorf("unsupported command %T", cmd)
+	}
+	return nil
+}
+
+func checkAssertion(kernel *bettirdl.Kernel, a AssertCommand) error {
+	tel := kernel.GetTelemetry()
+
+	var got uint64
+	switch a.Field {
+	case "events":
+		got = tel.EventsProcessed
+	case "time":
+		got = tel.CurrentTime
+	case "processes":
+		got = tel.ProcessCount
+	case "memory":
+		got = tel.MemoryUsed
+	default:
+		return fmt.Errorf("unknown assertion field %q", a.Field)
+	}
+
+	var ok bool
+	switch a.Op {
+	case ">=":
+		ok = got >= a.Value
+	case "<=":
+		ok = got <= a.Value
+	case "==":
+		ok = got == a.Value
+	case "!=":
+		ok = got != a.Value
+	case ">":
+		ok = got > a.Value
+	case "<":
+		ok = got < a.Value
+	default:
+		return fmt.Errorf("unknown assertion operator %q", a.Op)
+	}
+	if !ok {
+		return fmt.Errorf("assertion failed: %s%s%d (got %d)", a.Field, a.Op, a.Value, got)
+	}
+	return nil
+}
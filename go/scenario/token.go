@@ -0,0 +1,61 @@
+// Package scenario implements a small text DSL for driving a
+// bettirdl.Kernel through a reproducible sequence of commands, e.g.:
+//
+//	spawn 3 4 5
+//	inject 3 4 5 value=7
+//	run 100
+//	assert events>=42
+//	checkpoint out.h5
+//
+// Tokenizing, parsing, and execution are kept as separate stages
+// (Tokenize, Parse, Program.Run) so tools can lint or transform
+// scenarios without having to execute them against a real kernel.
+package scenario
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// TokenKind classifies a lexical token produced by Tokenize.
+type TokenKind int
+
+const (
+	// Word is a bare whitespace-delimited token, e.g. "spawn", "3",
+	// "value=7", or "out.h5". Tokenize does no further classification;
+	// Parse is responsible for interpreting a Word in context.
+	Word TokenKind = iota
+	// EOF marks the end of the token stream.
+	EOF
+)
+
+// Token is one lexical unit of a scenario file, along with the
+// 1-based source line it came from, used for error messages.
+type Token struct {
+	Kind TokenKind
+	Text string
+	Line int
+}
+
+// Tokenize splits r into whitespace-delimited tokens, one source line
+// at a time. Blank lines and lines whose first non-blank character is
+// '#' are skipped, so scenario files can carry comments. The returned
+// slice always ends with an EOF token.
+func Tokenize(r io.Reader) []Token {
+	var tokens []Token
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		for _, word := range strings.Fields(text) {
+			tokens = append(tokens, Token{Kind: Word, Text: word, Line: line})
+		}
+	}
+	tokens = append(tokens, Token{Kind: EOF, Line: line})
+	return tokens
+}
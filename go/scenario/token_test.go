@@ -0,0 +1,64 @@
+package scenario
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenizeSplitsWordsByLine(t *testing.T) {
+	input := "spawn 3 4 5\ninject 3 4 5 value=7\nrun 100\n"
+	tokens := Tokenize(strings.NewReader(input))
+
+	want := []Token{
+		{Kind: Word, Text: "spawn", Line: 1},
+		{Kind: Word, Text: "3", Line: 1},
+		{Kind: Word, Text: "4", Line: 1},
+		{Kind: Word, Text: "5", Line: 1},
+		{Kind: Word, Text: "inject", Line: 2},
+		{Kind: Word, Text: "3", Line: 2},
+		{Kind: Word, Text: "4", Line: 2},
+		{Kind: Word, Text: "5", Line: 2},
+		{Kind: Word, Text: "value=7", Line: 2},
+		{Kind: Word, Text: "run", Line: 3},
+		{Kind: Word, Text: "100", Line: 3},
+		{Kind: EOF, Line: 3},
+	}
+
+	if len(tokens) != len(want) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i, tok := range tokens {
+		if tok != want[i] {
+			t.Errorf("token %d = %+v, want %+v", i, tok, want[i])
+		}
+	}
+}
+
+func TestTokenizeSkipsBlankLinesAndComments(t *testing.T) {
+	input := "\n# a comment\n  \nspawn 0 0 0\n"
+	tokens := Tokenize(strings.NewReader(input))
+
+	var words []string
+	for _, tok := range tokens {
+		if tok.Kind == Word {
+			words = append(words, tok.Text)
+		}
+	}
+
+	want := []string{"spawn", "0", "0", "0"}
+	if len(words) != len(want) {
+		t.Fatalf("got words %v, want %v", words, want)
+	}
+	for i, w := range words {
+		if w != want[i] {
+			t.Errorf("word %d = %q, want %q", i, w, want[i])
+		}
+	}
+}
+
+func TestTokenizeEmptyInputYieldsOnlyEOF(t *testing.T) {
+	tokens := Tokenize(strings.NewReader(""))
+	if len(tokens) != 1 || tokens[0].Kind != EOF {
+		t.Fatalf("Tokenize(\"\") = %+v, want a single EOF token", tokens)
+	}
+}
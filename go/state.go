@@ -0,0 +1,52 @@
+package bettirdl
+
+/*
+#include <stdint.h>
+#include <stddef.h>
+
+typedef struct BettiRDLCompute BettiRDLCompute;
+
+// Flat buffer serialization of the full kernel state, used by
+// bettirdl/checkpoint to build HDF5 checkpoints without requiring this
+// package itself to link against libhdf5.
+uint8_t* betti_rdl_serialize_state(const BettiRDLCompute* kernel, size_t* out_len);
+int betti_rdl_load_state(BettiRDLCompute* kernel, const uint8_t* buf, size_t len);
+void betti_rdl_free_buffer(uint8_t* buf);
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// SerializeState returns a flat, opaque snapshot of the kernel's full
+// simulation state (current time, per-process state, pending events).
+// It has no HDF5 involvement; bettirdl/checkpoint decodes the buffer to
+// build an HDF5 checkpoint, but any caller can use it to clone or
+// transplant a kernel's state on its own.
+func (k *Kernel) SerializeState() ([]byte, error) {
+	var length C.size_t
+	buf := C.betti_rdl_serialize_state(k.ptr, &length)
+	if buf == nil {
+		return nil, fmt.Errorf("bettirdl: serialize kernel state: kernel returned no data")
+	}
+	defer C.betti_rdl_free_buffer(buf)
+	return C.GoBytes(unsafe.Pointer(buf), C.int(length)), nil
+}
+
+// LoadState creates a new Kernel restored to the state encoded in raw, a
+// buffer previously produced by SerializeState (possibly by a different
+// kernel instance).
+func LoadState(raw []byte) (*Kernel, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("bettirdl: load kernel state: empty buffer")
+	}
+	k := NewKernel()
+	ret := C.betti_rdl_load_state(k.ptr, (*C.uint8_t)(unsafe.Pointer(&raw[0])), C.size_t(len(raw)))
+	if ret != 0 {
+		k.Close()
+		return nil, fmt.Errorf("bettirdl: load kernel state: kernel rejected buffer (code %d)", int(ret))
+	}
+	return k, nil
+}
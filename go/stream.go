@@ -0,0 +1,160 @@
+package bettirdl
+
+/*
+#include <stddef.h>
+
+typedef struct BettiRDLCompute BettiRDLCompute;
+
+// betti_rdl_run_step advances the kernel by at most budget events and
+// returns without releasing it, so the Go side can drive the run loop
+// itself and interleave work (cancellation checks, telemetry sampling)
+// between steps. See RunContext in stream.go.
+int betti_rdl_run_step(BettiRDLCompute* kernel, int budget);
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// runStepBudget bounds how many events betti_rdl_run_step processes
+// before returning control to RunContext, keeping cancellation and
+// sampling responsive even for long runs.
+const runStepBudget = 256
+
+// Sample is one point-in-time observation taken while RunContext drives
+// a kernel: the kernel's own deterministic telemetry alongside host-side
+// resource usage for the current process.
+type Sample struct {
+	Telemetry  Telemetry
+	CPUPercent float64
+	RSSBytes   uint64
+}
+
+// RunOptions configures RunContext.
+type RunOptions struct {
+	// SampleInterval is how often to emit a Sample while the kernel
+	// runs. Zero (the default) disables sampling.
+	SampleInterval time.Duration
+	// Sink receives a Sample roughly every SampleInterval, plus a final
+	// sample once RunContext returns. RunContext never closes Sink; the
+	// caller owns its lifetime. A full Sink drops the sample rather
+	// than blocking the run loop.
+	Sink chan<- Sample
+}
+
+// RunContext is a cancellable, observable variant of Run. It drives the
+// kernel in runStepBudget-sized steps via betti_rdl_run_step instead of
+// one blocking call, so it can honor ctx.Done() between steps. If
+// opts.Sink is set, a background goroutine emits telemetry/host-resource
+// Samples on opts.SampleInterval, independent of step boundaries. A
+// mutex serializes that goroutine's calls into the kernel against the
+// run loop's, since nothing guarantees the C++ kernel's getters are
+// safe to call while a step is in flight.
+//
+// It returns the number of events processed before maxEvents was
+// reached, ctx was canceled, or the kernel ran out of events to
+// process.
+func (k *Kernel) RunContext(ctx context.Context, maxEvents int, opts RunOptions) (int, error) {
+	var mu sync.Mutex // serializes kernel access between the run loop and the sampler goroutine
+
+	var proc *process.Process
+	var samplerDone chan struct{}
+	var stopSampler context.CancelFunc
+	if opts.SampleInterval > 0 && opts.Sink != nil {
+		p, err := process.NewProcess(int32(os.Getpid()))
+		if err != nil {
+			return 0, fmt.Errorf("bettirdl: resolve host process for sampling: %w", err)
+		}
+		proc = p
+
+		var samplerCtx context.Context
+		samplerCtx, stopSampler = context.WithCancel(ctx)
+		samplerDone = make(chan struct{})
+		go k.sampleLoop(samplerCtx, samplerDone, &mu, proc, opts)
+	}
+	stop := func() {
+		if stopSampler != nil {
+			stopSampler()
+			<-samplerDone
+		}
+	}
+
+	total := 0
+	for total < maxEvents {
+		select {
+		case <-ctx.Done():
+			stop()
+			return total, ctx.Err()
+		default:
+		}
+
+		budget := runStepBudget
+		if remaining := maxEvents - total; remaining < budget {
+			budget = remaining
+		}
+
+		mu.Lock()
+		processed := int(C.betti_rdl_run_step(k.ptr, C.int(budget)))
+		mu.Unlock()
+
+		total += processed
+		if processed == 0 {
+			break // kernel has no more events to process
+		}
+	}
+	stop()
+
+	if opts.Sink != nil {
+		mu.Lock()
+		k.emitSample(proc, opts.Sink)
+		mu.Unlock()
+	}
+	return total, nil
+}
+
+// sampleLoop runs as a background goroutine for the lifetime of a
+// RunContext call, emitting a Sample every opts.SampleInterval until ctx
+// is canceled. It acquires mu for each sample so its kernel reads never
+// interleave with a concurrent betti_rdl_run_step call.
+func (k *Kernel) sampleLoop(ctx context.Context, done chan<- struct{}, mu *sync.Mutex, proc *process.Process, opts RunOptions) {
+	defer close(done)
+
+	ticker := time.NewTicker(opts.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mu.Lock()
+			k.emitSample(proc, opts.Sink)
+			mu.Unlock()
+		}
+	}
+}
+
+func (k *Kernel) emitSample(proc *process.Process, sink chan<- Sample) {
+	sample := Sample{Telemetry: k.GetTelemetry()}
+	if proc != nil {
+		if cpu, err := proc.CPUPercent(); err == nil {
+			sample.CPUPercent = cpu
+		}
+		if mem, err := proc.MemoryInfo(); err == nil && mem != nil {
+			sample.RSSBytes = mem.RSS
+		}
+	}
+	select {
+	case sink <- sample:
+	default:
+		// Drop the sample rather than block the simulation loop if the
+		// caller isn't draining the sink fast enough.
+	}
+}